@@ -0,0 +1,181 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package consul provides a Consul-backed implementation of the
+// targets.TargetManager contract, using the Consul KV store as the
+// source of truth for global sparrow peers instead of a GitLab project.
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/caas-team/sparrow/pkg/checks"
+	"github.com/hashicorp/consul/api"
+)
+
+//go:generate moq -out consul_moq.go . Consul
+
+// Consul abstracts the Consul session/KV operations needed to register a
+// sparrow instance and discover its peers, so it can be mocked in tests.
+type Consul interface {
+	// Register writes a session-bound KV entry for key, creating or
+	// renewing the session backing this instance's registration. The
+	// session's Behavior is "delete", so the entry is removed automatically
+	// once the instance stops renewing it, i.e. once it is unhealthy.
+	Register(ctx context.Context, key string, target checks.GlobalTarget) error
+	// Deregister destroys the session backing this instance's registration,
+	// immediately removing its KV entry
+	Deregister(ctx context.Context) error
+	// ListTargets performs a blocking KV query rooted at the configured key
+	// prefix, returning once the tree changes or the query times out. It
+	// returns every currently registered target and the KV index observed,
+	// which callers should pass back in as waitIndex to block again.
+	ListTargets(ctx context.Context, waitIndex uint64) (targets []checks.GlobalTarget, index uint64, err error)
+}
+
+// Config configures the Consul client
+type Config struct {
+	// Address is the address of the Consul HTTP API, e.g. "127.0.0.1:8500"
+	Address string `yaml:"address" mapstructure:"address"`
+	// Token is the ACL token used to authenticate against Consul
+	Token string `yaml:"token" mapstructure:"token"`
+	// KeyPrefix is the KV prefix under which global targets are registered
+	KeyPrefix string `yaml:"keyPrefix" mapstructure:"keyPrefix"`
+	// TTL is the session TTL. An instance is considered unhealthy, and its
+	// registration is removed by Consul, once its session has not been
+	// renewed for this long - this is the Consul equivalent of
+	// gitlabTargetManager's unhealthyThreshold.
+	TTL time.Duration `yaml:"ttl" mapstructure:"ttl"`
+}
+
+var _ Consul = (*client)(nil)
+
+type client struct {
+	api       *api.Client
+	keyPrefix string
+	ttl       time.Duration
+
+	mu        sync.Mutex
+	sessionID string
+}
+
+// New creates a new Consul client from cfg
+func New(cfg Config) (Consul, error) {
+	c, err := api.NewClient(&api.Config{
+		Address: cfg.Address,
+		Token:   cfg.Token,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consul client: %w", err)
+	}
+
+	return &client{
+		api:       c,
+		keyPrefix: cfg.KeyPrefix,
+		ttl:       cfg.TTL,
+	}, nil
+}
+
+// Register implements Consul
+func (c *client) Register(ctx context.Context, key string, target checks.GlobalTarget) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sessionID == "" {
+		id, _, err := c.api.Session().CreateNoChecks(&api.SessionEntry{
+			Name:     key,
+			TTL:      c.ttl.String(),
+			Behavior: api.SessionBehaviorDelete,
+		}, (&api.WriteOptions{}).WithContext(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to create consul session: %w", err)
+		}
+		c.sessionID = id
+	} else if entry, _, err := c.api.Session().Renew(c.sessionID, (&api.WriteOptions{}).WithContext(ctx)); err != nil || entry == nil {
+		// the session may have expired server-side (Renew then returns no
+		// entry); drop it so the next Register call creates a fresh one
+		c.sessionID = ""
+		if err == nil {
+			err = fmt.Errorf("session not found")
+		}
+		return fmt.Errorf("failed to renew consul session: %w", err)
+	}
+
+	payload, err := json.Marshal(target)
+	if err != nil {
+		return fmt.Errorf("failed to marshal target: %w", err)
+	}
+
+	acquired, _, err := c.api.KV().Acquire(&api.KVPair{
+		Key:     path.Join(c.keyPrefix, key),
+		Value:   payload,
+		Session: c.sessionID,
+	}, (&api.WriteOptions{}).WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to write consul kv entry: %w", err)
+	}
+	if !acquired {
+		return fmt.Errorf("failed to acquire consul kv lock for key %q", key)
+	}
+
+	return nil
+}
+
+// Deregister implements Consul
+func (c *client) Deregister(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sessionID == "" {
+		return nil
+	}
+
+	_, err := c.api.Session().Destroy(c.sessionID, (&api.WriteOptions{}).WithContext(ctx))
+	c.sessionID = ""
+	if err != nil {
+		return fmt.Errorf("failed to destroy consul session: %w", err)
+	}
+
+	return nil
+}
+
+// ListTargets implements Consul
+func (c *client) ListTargets(ctx context.Context, waitIndex uint64) ([]checks.GlobalTarget, uint64, error) {
+	pairs, meta, err := c.api.KV().List(c.keyPrefix, (&api.QueryOptions{
+		WaitIndex: waitIndex,
+	}).WithContext(ctx))
+	if err != nil {
+		return nil, waitIndex, fmt.Errorf("failed to list consul kv entries: %w", err)
+	}
+
+	var targets []checks.GlobalTarget
+	for _, pair := range pairs {
+		var target checks.GlobalTarget
+		if err := json.Unmarshal(pair.Value, &target); err != nil {
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	return targets, meta.LastIndex, nil
+}
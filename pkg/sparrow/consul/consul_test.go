@@ -0,0 +1,187 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/caas-team/sparrow/pkg/checks"
+)
+
+// newTestClient builds a client against an httptest.Server serving the
+// Consul HTTP API endpoints registered on handler
+func newTestClient(t *testing.T, handler http.Handler) *client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c, err := New(Config{
+		Address:   strings.TrimPrefix(srv.URL, "http://"),
+		KeyPrefix: "sparrow",
+		TTL:       10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return c.(*client)
+}
+
+func Test_client_Register_createsThenRenewsSession(t *testing.T) {
+	var sessionCreates, sessionRenews, kvAcquires int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/session/create", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&sessionCreates, 1)
+		_ = json.NewEncoder(w).Encode(map[string]string{"ID": "sess-1"})
+	})
+	mux.HandleFunc("/v1/session/renew/sess-1", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&sessionRenews, 1)
+		_ = json.NewEncoder(w).Encode([]map[string]any{{"ID": "sess-1"}})
+	})
+	mux.HandleFunc("/v1/kv/sparrow/peer1", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&kvAcquires, 1)
+		fmt.Fprint(w, "true")
+	})
+
+	c := newTestClient(t, mux)
+	target := checks.GlobalTarget{Url: "https://peer1", LastSeen: time.Now().UTC()}
+
+	if err := c.Register(context.Background(), "peer1", target); err != nil {
+		t.Fatalf("Register() first call error = %v", err)
+	}
+	if c.sessionID != "sess-1" {
+		t.Fatalf("sessionID = %q, want %q", c.sessionID, "sess-1")
+	}
+	if atomic.LoadInt32(&sessionCreates) != 1 {
+		t.Fatalf("expected 1 session create, got %d", sessionCreates)
+	}
+
+	if err := c.Register(context.Background(), "peer1", target); err != nil {
+		t.Fatalf("Register() second call error = %v", err)
+	}
+	if atomic.LoadInt32(&sessionCreates) != 1 {
+		t.Fatalf("expected no additional session create on second Register, got %d total", sessionCreates)
+	}
+	if atomic.LoadInt32(&sessionRenews) != 1 {
+		t.Fatalf("expected 1 session renew on second Register, got %d", sessionRenews)
+	}
+	if atomic.LoadInt32(&kvAcquires) != 2 {
+		t.Fatalf("expected 2 kv acquires, got %d", kvAcquires)
+	}
+}
+
+func Test_client_Register_dropsSessionOnRenewFailure(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/session/create", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"ID": "sess-1"})
+	})
+	mux.HandleFunc("/v1/session/renew/sess-1", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/v1/kv/sparrow/peer1", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "true")
+	})
+
+	c := newTestClient(t, mux)
+
+	if err := c.Register(context.Background(), "peer1", checks.GlobalTarget{}); err != nil {
+		t.Fatalf("Register() first call error = %v", err)
+	}
+
+	if err := c.Register(context.Background(), "peer1", checks.GlobalTarget{}); err == nil {
+		t.Fatal("Register() second call error = nil, want error when session renew fails")
+	}
+	if c.sessionID != "" {
+		t.Fatalf("sessionID = %q, want empty after a failed renew so the next Register creates a fresh session", c.sessionID)
+	}
+}
+
+func Test_client_Deregister(t *testing.T) {
+	var destroyed int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/session/create", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"ID": "sess-1"})
+	})
+	mux.HandleFunc("/v1/kv/sparrow/peer1", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "true")
+	})
+	mux.HandleFunc("/v1/session/destroy/sess-1", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&destroyed, 1)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	c := newTestClient(t, mux)
+
+	if err := c.Register(context.Background(), "peer1", checks.GlobalTarget{}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if err := c.Deregister(context.Background()); err != nil {
+		t.Fatalf("Deregister() error = %v", err)
+	}
+	if atomic.LoadInt32(&destroyed) != 1 {
+		t.Fatalf("expected session to be destroyed once, got %d", destroyed)
+	}
+	if c.sessionID != "" {
+		t.Fatalf("sessionID = %q, want empty after Deregister", c.sessionID)
+	}
+
+	// Deregister with no active session is a no-op; it must not call the API
+	if err := c.Deregister(context.Background()); err != nil {
+		t.Fatalf("Deregister() with no session error = %v, want nil", err)
+	}
+}
+
+func Test_client_ListTargets(t *testing.T) {
+	target := checks.GlobalTarget{Url: "https://peer1", LastSeen: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	value, err := json.Marshal(target)
+	if err != nil {
+		t.Fatalf("failed to marshal target: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/kv/sparrow", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Consul-Index", "42")
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"Key": "sparrow/peer1", "Value": value, "Session": "sess-1"},
+		})
+	})
+
+	c := newTestClient(t, mux)
+
+	got, index, err := c.ListTargets(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("ListTargets() error = %v", err)
+	}
+	if index != 42 {
+		t.Fatalf("ListTargets() index = %d, want 42", index)
+	}
+	if len(got) != 1 || got[0].Url != target.Url {
+		t.Fatalf("ListTargets() got = %v, want [%v]", got, target)
+	}
+}
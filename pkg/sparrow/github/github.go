@@ -0,0 +1,356 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package github provides a GitHub Contents API client scoped to a single
+// repository directory, used to read and write the files that make up the
+// global target list. It is a peer of pkg/sparrow/gitlab, used by the
+// github targets.Backend.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/caas-team/sparrow/pkg/checks"
+)
+
+//go:generate moq -out github_moq.go . Github
+
+// Github abstracts the GitHub API operations needed by the github target
+// manager, so it can be mocked in tests.
+type Github interface {
+	// FetchFiles fetches the content of every file in the configured
+	// directory and parses it as a checks.GlobalTarget
+	FetchFiles(ctx context.Context) ([]checks.GlobalTarget, error)
+	// PutFile updates an existing file, performing the required sha
+	// handshake first
+	PutFile(ctx context.Context, file File) error
+	// PostFile creates a new file
+	PostFile(ctx context.Context, file File) error
+}
+
+var _ Github = (*Client)(nil)
+
+const defaultBaseURL = "https://api.github.com"
+
+// Client is a GitHub Contents API client scoped to a single repository
+// directory.
+type Client struct {
+	baseUrl string
+	owner   string
+	repo    string
+	dir     string
+	tokens  TokenSource
+	client  *http.Client
+
+	mu    sync.Mutex
+	etags map[string]string
+	cache map[string]checks.GlobalTarget
+	shas  map[string]string
+}
+
+// NewClient creates a new github Client. dir is the directory within the
+// repository that holds the global target files; pass "" for the
+// repository root.
+func NewClient(owner, repo, dir string, tokens TokenSource) *Client {
+	return &Client{
+		baseUrl: defaultBaseURL,
+		owner:   owner,
+		repo:    repo,
+		dir:     strings.Trim(dir, "/"),
+		tokens:  tokens,
+		client:  http.DefaultClient,
+		etags:   make(map[string]string),
+		cache:   make(map[string]checks.GlobalTarget),
+		shas:    make(map[string]string),
+	}
+}
+
+// File is a file tracked in the GitHub repository backing the global
+// target list
+type File struct {
+	Content       checks.GlobalTarget
+	CommitMessage string
+	fileName      string
+}
+
+// name returns the file's path within the configured directory. If
+// fileName was not set explicitly, it is derived from the target URL it
+// registers.
+func (f File) name() string {
+	if f.fileName != "" {
+		return f.fileName
+	}
+	name := strings.TrimPrefix(f.Content.Url, "https://")
+	name = strings.TrimPrefix(name, "http://")
+	if name == "" {
+		return ""
+	}
+	return name + ".json"
+}
+
+// FetchFiles implements Github
+func (c *Client) FetchFiles(ctx context.Context) ([]checks.GlobalTarget, error) {
+	paths, err := c.listDir(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory: %w", err)
+	}
+
+	var targets []checks.GlobalTarget
+	for _, path := range paths {
+		target, err := c.fetchFile(ctx, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch file %q: %w", path, err)
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+func (c *Client) listDir(ctx context.Context) ([]string, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.contentsURL(c.dir), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d while listing directory", resp.StatusCode)
+	}
+
+	var entries []struct {
+		Path string `json:"path"`
+		Type string `json:"type"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to decode directory listing: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.Type != "file" {
+			continue
+		}
+		paths = append(paths, entry.Path)
+	}
+	return paths, nil
+}
+
+// fetchFile fetches a single file's content and parses it as a
+// checks.GlobalTarget, reusing the cached value when GitHub reports it as
+// unchanged via a 304 Not Modified response to a conditional request.
+func (c *Client) fetchFile(ctx context.Context, path string) (checks.GlobalTarget, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.contentsURL(path), nil)
+	if err != nil {
+		return checks.GlobalTarget{}, err
+	}
+
+	c.mu.Lock()
+	etag, haveETag := c.etags[path]
+	cached, haveCached := c.cache[path]
+	c.mu.Unlock()
+	if haveETag {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return checks.GlobalTarget{}, fmt.Errorf("failed to fetch file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && haveCached {
+		return cached, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return checks.GlobalTarget{}, fmt.Errorf("unexpected status code %d while fetching file", resp.StatusCode)
+	}
+
+	var body struct {
+		SHA     string `json:"sha"`
+		Content string `json:"content"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return checks.GlobalTarget{}, fmt.Errorf("failed to decode file: %w", err)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(body.Content, "\n", ""))
+	if err != nil {
+		return checks.GlobalTarget{}, fmt.Errorf("failed to decode file content: %w", err)
+	}
+
+	var target checks.GlobalTarget
+	if err := json.Unmarshal(raw, &target); err != nil {
+		return checks.GlobalTarget{}, fmt.Errorf("failed to parse target: %w", err)
+	}
+
+	c.mu.Lock()
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		c.etags[path] = etag
+	}
+	c.cache[path] = target
+	c.shas[path] = body.SHA
+	c.mu.Unlock()
+
+	return target, nil
+}
+
+// PutFile implements Github. It performs the sha handshake required by the
+// Contents API to update an existing file, fetching the current sha first
+// if it has not already been observed.
+func (c *Client) PutFile(ctx context.Context, file File) error {
+	return c.pushFile(ctx, file, true)
+}
+
+// PostFile implements Github
+func (c *Client) PostFile(ctx context.Context, file File) error {
+	return c.pushFile(ctx, file, false)
+}
+
+func (c *Client) pushFile(ctx context.Context, file File, update bool) error {
+	path := file.name()
+	if c.dir != "" {
+		path = c.dir + "/" + path
+	}
+
+	content, err := json.Marshal(file.Content)
+	if err != nil {
+		return fmt.Errorf("failed to marshal target: %w", err)
+	}
+
+	reqBody := struct {
+		Message string `json:"message"`
+		Content string `json:"content"`
+		SHA     string `json:"sha,omitempty"`
+	}{
+		Message: file.CommitMessage,
+		Content: base64.StdEncoding.EncodeToString(content),
+	}
+
+	if update {
+		sha, err := c.currentSHA(ctx, path)
+		if err != nil {
+			return fmt.Errorf("failed to resolve current file sha: %w", err)
+		}
+		reqBody.SHA = sha
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPut, c.contentsURL(path), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("unexpected status code %d while pushing file", resp.StatusCode)
+	}
+
+	var result struct {
+		Content struct {
+			SHA string `json:"sha"`
+		} `json:"content"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err == nil && result.Content.SHA != "" {
+		c.mu.Lock()
+		c.shas[path] = result.Content.SHA
+		c.mu.Unlock()
+	}
+
+	return nil
+}
+
+// currentSHA returns the blob sha GitHub currently has stored for path,
+// fetching it if it was not already observed by a previous fetch or push.
+func (c *Client) currentSHA(ctx context.Context, path string) (string, error) {
+	c.mu.Lock()
+	sha, ok := c.shas[path]
+	c.mu.Unlock()
+	if ok {
+		return sha, nil
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, c.contentsURL(path), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch current file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d while resolving sha", resp.StatusCode)
+	}
+
+	var respBody struct {
+		SHA string `json:"sha"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
+		return "", fmt.Errorf("failed to decode file metadata: %w", err)
+	}
+
+	c.mu.Lock()
+	c.shas[path] = respBody.SHA
+	c.mu.Unlock()
+	return respBody.SHA, nil
+}
+
+func (c *Client) contentsURL(path string) string {
+	return fmt.Sprintf("%s/repos/%s/%s/contents/%s", c.baseUrl, c.owner, c.repo, path)
+}
+
+func (c *Client) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	return req, nil
+}
@@ -0,0 +1,159 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/caas-team/sparrow/pkg/checks"
+	"github.com/jarcoal/httpmock"
+)
+
+func newTestClient() *Client {
+	return &Client{
+		baseUrl: "http://test",
+		owner:   "o",
+		repo:    "r",
+		dir:     "targets",
+		tokens:  PATTokenSource("test"),
+		client:  http.DefaultClient,
+		etags:   make(map[string]string),
+		cache:   make(map[string]checks.GlobalTarget),
+		shas:    make(map[string]string),
+	}
+}
+
+// Test_Client_fetchFile_conditionalRequests verifies that fetchFile sends
+// If-None-Match once an ETag has been observed, and serves the cached target
+// without re-decoding a body when the server responds 304 Not Modified.
+func Test_Client_fetchFile_conditionalRequests(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	c := newTestClient()
+	target := checks.GlobalTarget{Url: "https://peer1", LastSeen: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	raw, err := json.Marshal(target)
+	if err != nil {
+		t.Fatalf("failed to marshal target: %v", err)
+	}
+
+	calls := 0
+	httpmock.RegisterResponder("GET", "http://test/repos/o/r/contents/targets/peer1.json",
+		func(req *http.Request) (*http.Response, error) {
+			calls++
+			if req.Header.Get("If-None-Match") == `"etag-1"` {
+				return httpmock.NewStringResponse(http.StatusNotModified, ""), nil
+			}
+			resp, err := httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+				"sha":     "sha-1",
+				"content": raw,
+			})
+			if err != nil {
+				return nil, err
+			}
+			resp.Header.Set("ETag", `"etag-1"`)
+			return resp, nil
+		},
+	)
+
+	got, err := c.fetchFile(context.Background(), "targets/peer1.json")
+	if err != nil {
+		t.Fatalf("fetchFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, target) {
+		t.Fatalf("fetchFile() got = %v, want %v", got, target)
+	}
+
+	got, err = c.fetchFile(context.Background(), "targets/peer1.json")
+	if err != nil {
+		t.Fatalf("fetchFile() second call error = %v", err)
+	}
+	if !reflect.DeepEqual(got, target) {
+		t.Fatalf("fetchFile() second call got = %v, want %v", got, target)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 requests to be made, got %d", calls)
+	}
+}
+
+// Test_Client_PutFile_performsShaHandshake verifies that PutFile resolves the
+// current sha via a GET before its first PUT, then reuses the sha returned by
+// that PUT on subsequent updates without fetching it again.
+func Test_Client_PutFile_performsShaHandshake(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	c := newTestClient()
+	file := File{CommitMessage: "update", Content: checks.GlobalTarget{Url: "https://peer1", LastSeen: time.Now().UTC()}}
+
+	getCalls := 0
+	httpmock.RegisterResponder("GET", "http://test/repos/o/r/contents/targets/peer1.json",
+		func(_ *http.Request) (*http.Response, error) {
+			getCalls++
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]any{"sha": "sha-1"})
+		},
+	)
+
+	var lastSHA string
+	putCalls := 0
+	httpmock.RegisterResponder("PUT", "http://test/repos/o/r/contents/targets/peer1.json",
+		func(req *http.Request) (*http.Response, error) {
+			putCalls++
+			var body struct {
+				SHA string `json:"sha"`
+			}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+			lastSHA = body.SHA
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+				"content": map[string]any{"sha": "sha-2"},
+			})
+		},
+	)
+
+	file.fileName = "peer1.json"
+	if err := c.PutFile(context.Background(), file); err != nil {
+		t.Fatalf("PutFile() first call error = %v", err)
+	}
+	if lastSHA != "sha-1" {
+		t.Fatalf("PUT body sha = %q, want %q from the resolved current sha", lastSHA, "sha-1")
+	}
+	if getCalls != 1 {
+		t.Fatalf("expected 1 GET to resolve the current sha, got %d", getCalls)
+	}
+
+	if err := c.PutFile(context.Background(), file); err != nil {
+		t.Fatalf("PutFile() second call error = %v", err)
+	}
+	if lastSHA != "sha-2" {
+		t.Fatalf("PUT body sha = %q, want %q reused from the first PUT's response", lastSHA, "sha-2")
+	}
+	if getCalls != 1 {
+		t.Fatalf("expected no additional GET once the sha was cached from the first PUT, got %d total", getCalls)
+	}
+	if putCalls != 2 {
+		t.Fatalf("expected 2 PUT requests, got %d", putCalls)
+	}
+}
@@ -0,0 +1,167 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TokenSource returns the bearer token attached to every request made by a
+// Client. It is implemented by PATTokenSource for a static personal access
+// token and by AppTokenSource for a GitHub App installation.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// PATTokenSource is a TokenSource backed by a static personal access token
+type PATTokenSource string
+
+// Token implements TokenSource
+func (t PATTokenSource) Token(_ context.Context) (string, error) {
+	return string(t), nil
+}
+
+var _ TokenSource = PATTokenSource("")
+
+// AppTokenSource is a TokenSource backed by a GitHub App installation. It
+// signs a short-lived JWT with the app's private key to exchange it for an
+// installation access token, and transparently refreshes the token shortly
+// before it expires.
+type AppTokenSource struct {
+	AppID          int64
+	InstallationID int64
+	PrivateKeyPEM  []byte
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+var _ TokenSource = (*AppTokenSource)(nil)
+
+// tokenRefreshMargin is how long before expiry the installation token is
+// refreshed
+const tokenRefreshMargin = time.Minute
+
+// Token implements TokenSource
+func (t *AppTokenSource) Token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && time.Until(t.expiresAt) > tokenRefreshMargin {
+		return t.token, nil
+	}
+
+	token, expiresAt, err := t.fetchInstallationToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch github app installation token: %w", err)
+	}
+
+	t.token = token
+	t.expiresAt = expiresAt
+	return token, nil
+}
+
+func (t *AppTokenSource) fetchInstallationToken(ctx context.Context) (string, time.Time, error) {
+	jwtToken, err := t.signAppJWT()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", defaultBaseURL, t.InstallationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to request installation token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", time.Time{}, fmt.Errorf("unexpected status code %d requesting installation token", resp.StatusCode)
+	}
+
+	var body struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decode installation token response: %w", err)
+	}
+
+	return body.Token, body.ExpiresAt, nil
+}
+
+// signAppJWT builds and signs the short-lived JWT GitHub requires to
+// identify the app when minting an installation access token
+func (t *AppTokenSource) signAppJWT() (string, error) {
+	block, _ := pem.Decode(t.PrivateKeyPEM)
+	if block == nil {
+		return "", fmt.Errorf("failed to decode private key PEM")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	now := time.Now()
+	header := map[string]string{"alg": "RS256", "typ": "JWT"}
+	claims := map[string]any{
+		// backdate iat slightly to tolerate clock drift between us and GitHub
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": t.AppID,
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal jwt header: %w", err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal jwt claims: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	hashed := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign jwt: %w", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
@@ -0,0 +1,119 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func testPrivateKeyPEM(t *testing.T) []byte {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate test rsa key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+}
+
+func Test_PATTokenSource_Token(t *testing.T) {
+	src := PATTokenSource("static-token")
+
+	got, err := src.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if got != "static-token" {
+		t.Fatalf("Token() = %q, want %q", got, "static-token")
+	}
+}
+
+// Test_AppTokenSource_Token_refreshesNearExpiry verifies that a cached
+// installation token is reused while it is valid, but refetched once it is
+// within tokenRefreshMargin of expiry.
+func Test_AppTokenSource_Token_refreshesNearExpiry(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	calls := 0
+	httpmock.RegisterResponder("POST", "https://api.github.com/app/installations/42/access_tokens",
+		func(_ *http.Request) (*http.Response, error) {
+			calls++
+			expiresAt := time.Now().Add(tokenRefreshMargin / 2) // inside tokenRefreshMargin next time round
+			if calls > 1 {
+				expiresAt = time.Now().Add(time.Hour)
+			}
+			return httpmock.NewJsonResponse(http.StatusCreated, map[string]any{
+				"token":      fmt.Sprintf("installation-token-%d", calls),
+				"expires_at": expiresAt,
+			})
+		},
+	)
+
+	src := &AppTokenSource{
+		AppID:          1,
+		InstallationID: 42,
+		PrivateKeyPEM:  testPrivateKeyPEM(t),
+	}
+
+	first, err := src.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() first call error = %v", err)
+	}
+	if first != "installation-token-1" {
+		t.Fatalf("Token() first call = %q, want %q", first, "installation-token-1")
+	}
+
+	// the first token expires within tokenRefreshMargin, so this call must
+	// fetch a fresh one rather than reusing it
+	second, err := src.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() second call error = %v", err)
+	}
+	if second != "installation-token-2" {
+		t.Fatalf("Token() second call = %q, want a freshly fetched token", second)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 installation token requests, got %d", calls)
+	}
+
+	// the second token has a long expiry, so this call must reuse it
+	third, err := src.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() third call error = %v", err)
+	}
+	if third != second {
+		t.Fatalf("Token() third call = %q, want cached token %q", third, second)
+	}
+	if calls != 2 {
+		t.Fatalf("expected no additional installation token request, got %d total", calls)
+	}
+}
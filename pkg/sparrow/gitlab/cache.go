@@ -0,0 +1,105 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gitlab
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/caas-team/sparrow/pkg/checks"
+)
+
+//go:generate moq -out cache_moq.go . Cache
+
+// CacheEntry is a single cached GitLab file, tagged with the ETag it was
+// fetched with so it can be revalidated via If-None-Match
+type CacheEntry struct {
+	ETag   string
+	Target checks.GlobalTarget
+}
+
+// Cache stores ETag-tagged file content, keyed by file name, so a Client
+// can be backed by something other than the default in-memory LRU - e.g.
+// an external store shared across multiple sparrow instances
+type Cache interface {
+	// Get returns the cached entry for key, if any
+	Get(key string) (entry CacheEntry, ok bool)
+	// Set stores entry for key
+	Set(key string, entry CacheEntry)
+}
+
+var _ Cache = (*lruCache)(nil)
+
+// lruCache is a fixed-size, in-memory, least-recently-used Cache. It is the
+// default Cache used by a Client.
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type lruNode struct {
+	key   string
+	entry CacheEntry
+}
+
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get implements Cache
+func (c *lruCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*lruNode).entry, true
+}
+
+// Set implements Cache
+func (c *lruCache) Set(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruNode).entry = entry
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	el := c.ll.PushFront(&lruNode{key: key, entry: entry})
+	c.items[key] = el
+
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruNode).key)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/caas-team/sparrow/pkg/checks"
+)
+
+func Test_lruCache_GetSet(t *testing.T) {
+	c := newLRUCache(2)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("Get() on empty cache returned ok = true")
+	}
+
+	c.Set("a", CacheEntry{ETag: "etag-a", Target: checks.GlobalTarget{Url: "a"}})
+	c.Set("b", CacheEntry{ETag: "etag-b", Target: checks.GlobalTarget{Url: "b"}})
+
+	got, ok := c.Get("a")
+	if !ok || got.ETag != "etag-a" {
+		t.Fatalf("Get(a) = %v, %v, want etag-a, true", got, ok)
+	}
+
+	// c is now full and "a" is the most recently used; adding "c" should
+	// evict "b", the least recently used entry
+	c.Set("c", CacheEntry{ETag: "etag-c", Target: checks.GlobalTarget{Url: "c"}})
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("Get(b) returned ok = true, want evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("Get(a) returned ok = false, want still present")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Fatalf("Get(c) returned ok = false, want present")
+	}
+}
+
+func Test_lruCache_SetOverwrites(t *testing.T) {
+	c := newLRUCache(2)
+
+	c.Set("a", CacheEntry{ETag: "etag-1"})
+	c.Set("a", CacheEntry{ETag: "etag-2"})
+
+	got, ok := c.Get("a")
+	if !ok || got.ETag != "etag-2" {
+		t.Fatalf("Get(a) = %v, %v, want etag-2, true", got, ok)
+	}
+}
@@ -0,0 +1,331 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package gitlab provides a GitLab API client scoped to a single project,
+// used to read and write the files that make up the global target list.
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/caas-team/sparrow/pkg/checks"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//go:generate moq -out gitlab_moq.go . Gitlab
+
+// Gitlab abstracts the GitLab API operations needed by the gitlab target
+// manager, so it can be mocked in tests.
+type Gitlab interface {
+	// FetchFiles fetches the content of every file in the configured
+	// project and parses it as a checks.GlobalTarget
+	FetchFiles(ctx context.Context) ([]checks.GlobalTarget, error)
+	// PutFile updates an existing file in the configured project
+	PutFile(ctx context.Context, file File) error
+	// PostFile creates a new file in the configured project
+	PostFile(ctx context.Context, file File) error
+}
+
+var _ Gitlab = (*Client)(nil)
+
+const defaultCacheSize = 256
+
+// Client is a GitLab API client scoped to a single project. Files fetched
+// via FetchFiles are cached by name and revalidated with If-None-Match on
+// every subsequent fetch, so a checkInterval tick that sees no changes
+// costs a round trip per file instead of a full payload per file.
+type Client struct {
+	baseUrl   string
+	projectID int
+	token     string
+	client    *http.Client
+
+	cache Cache // nil disables caching entirely
+
+	mu        sync.Mutex
+	treeETag  string
+	treeNames []string
+
+	cacheHits          prometheus.Counter
+	cacheMisses        prometheus.Counter
+	cacheRevalidations prometheus.Counter
+}
+
+// ClientOption configures optional behavior of a Client
+type ClientOption func(*Client)
+
+// WithCache overrides the default in-memory LRU cache backing ETag
+// revalidation, e.g. to share a cache across multiple Client instances or
+// back it with an external store
+func WithCache(cache Cache) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// NewClient creates a new gitlab Client. If registry is non-nil, counters
+// tracking cache hits, misses and revalidations are registered on it.
+func NewClient(baseUrl string, projectID int, token string, registry prometheus.Registerer, opts ...ClientOption) *Client {
+	c := &Client{
+		baseUrl:   baseUrl,
+		projectID: projectID,
+		token:     token,
+		client:    http.DefaultClient,
+		cache:     newLRUCache(defaultCacheSize),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if registry != nil {
+		c.cacheHits = prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "sparrow_gitlab_cache_hits_total",
+			Help: "The total number of GitLab file fetches served from the local ETag cache via a 304 Not Modified response",
+		})
+		c.cacheMisses = prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "sparrow_gitlab_cache_misses_total",
+			Help: "The total number of GitLab file fetches that had no cached ETag and required a full request",
+		})
+		c.cacheRevalidations = prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "sparrow_gitlab_cache_revalidations_total",
+			Help: "The total number of conditional GitLab file requests sent with an If-None-Match header",
+		})
+		registry.MustRegister(c.cacheHits, c.cacheMisses, c.cacheRevalidations)
+	}
+
+	return c
+}
+
+// File is a file tracked in the GitLab project backing the global target list
+type File struct {
+	Branch        string              `json:"branch"`
+	AuthorEmail   string              `json:"author_email"`
+	AuthorName    string              `json:"author_name"`
+	Content       checks.GlobalTarget `json:"content"`
+	CommitMessage string              `json:"commit_message"`
+	fileName      string
+}
+
+// name returns the file's path inside the project. If fileName was not set
+// explicitly, it is derived from the target URL it registers.
+func (f File) name() string {
+	if f.fileName != "" {
+		return f.fileName
+	}
+	name := strings.TrimPrefix(f.Content.Url, "https://")
+	name = strings.TrimPrefix(name, "http://")
+	if name == "" {
+		return ""
+	}
+	return name + ".json"
+}
+
+// FetchFiles fetches the content of every file in the configured project
+// and parses it as a checks.GlobalTarget
+func (c *Client) FetchFiles(ctx context.Context) ([]checks.GlobalTarget, error) {
+	names, err := c.fetchFileList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	return c.fetchFiles(ctx, names)
+}
+
+// fetchFileList lists the names of the files in the configured project
+func (c *Client) fetchFileList(ctx context.Context) ([]string, error) {
+	url := fmt.Sprintf("%s/api/v4/projects/%d/repository/tree?ref=main", c.baseUrl, c.projectID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+
+	c.mu.Lock()
+	etag := c.treeETag
+	cachedNames := c.treeNames
+	c.mu.Unlock()
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return cachedNames, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d while listing files", resp.StatusCode)
+	}
+
+	var files []struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&files); err != nil {
+		return nil, fmt.Errorf("failed to decode file list: %w", err)
+	}
+
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		c.mu.Lock()
+		c.treeETag = etag
+		c.treeNames = names
+		c.mu.Unlock()
+	}
+
+	return names, nil
+}
+
+// fetchFiles fetches the content of the given files and parses them as
+// checks.GlobalTarget
+func (c *Client) fetchFiles(ctx context.Context, fileList []string) ([]checks.GlobalTarget, error) {
+	var targets []checks.GlobalTarget
+	for _, name := range fileList {
+		target, err := c.fetchFile(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch file %q: %w", name, err)
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// fetchFile fetches a single file's content and parses it as a
+// checks.GlobalTarget, reusing the cached value when GitLab reports it as
+// unchanged via a 304 Not Modified response to a conditional request.
+func (c *Client) fetchFile(ctx context.Context, name string) (checks.GlobalTarget, error) {
+	url := fmt.Sprintf("%s/api/v4/projects/%d/repository/files/%s/raw?ref=main", c.baseUrl, c.projectID, name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return checks.GlobalTarget{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+
+	var cached CacheEntry
+	var haveCached bool
+	if c.cache != nil {
+		cached, haveCached = c.cache.Get(name)
+	}
+	if haveCached && cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+		c.incRevalidations()
+	} else {
+		c.incMisses()
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return checks.GlobalTarget{}, fmt.Errorf("failed to fetch file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && haveCached {
+		c.incHits()
+		return cached.Target, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return checks.GlobalTarget{}, fmt.Errorf("unexpected status code %d while fetching file", resp.StatusCode)
+	}
+
+	var target checks.GlobalTarget
+	if err := json.NewDecoder(resp.Body).Decode(&target); err != nil {
+		return checks.GlobalTarget{}, fmt.Errorf("failed to decode file: %w", err)
+	}
+
+	if c.cache != nil {
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			c.cache.Set(name, CacheEntry{ETag: etag, Target: target})
+		}
+	}
+
+	return target, nil
+}
+
+// PutFile updates an existing file in the configured project
+func (c *Client) PutFile(ctx context.Context, file File) error {
+	return c.pushFile(ctx, http.MethodPut, file)
+}
+
+// PostFile creates a new file in the configured project
+func (c *Client) PostFile(ctx context.Context, file File) error {
+	return c.pushFile(ctx, http.MethodPost, file)
+}
+
+func (c *Client) pushFile(ctx context.Context, method string, file File) error {
+	body, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to marshal file: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/v4/projects/%d/repository/files/%s", c.baseUrl, c.projectID, file.name())
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("unexpected status code %d while pushing file", resp.StatusCode)
+	}
+
+	// invalidate the tree cache, since the set of files has potentially changed
+	c.mu.Lock()
+	c.treeETag = ""
+	c.treeNames = nil
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *Client) incHits() {
+	if c.cacheHits != nil {
+		c.cacheHits.Inc()
+	}
+}
+
+func (c *Client) incMisses() {
+	if c.cacheMisses != nil {
+		c.cacheMisses.Inc()
+	}
+}
+
+func (c *Client) incRevalidations() {
+	if c.cacheRevalidations != nil {
+		c.cacheRevalidations.Inc()
+	}
+}
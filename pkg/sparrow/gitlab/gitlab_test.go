@@ -328,6 +328,62 @@ func TestClient_PutFile(t *testing.T) {
 	}
 }
 
+// Test_gitlab_fetchFile_conditionalRequests verifies that fetchFile sends
+// If-None-Match once an ETag has been observed, and serves the cached target
+// without re-decoding a body when the server responds 304 Not Modified.
+func Test_gitlab_fetchFile_conditionalRequests(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	g := &Client{
+		baseUrl:   "http://test",
+		projectID: 1,
+		token:     "test",
+		client:    http.DefaultClient,
+		cache:     newLRUCache(defaultCacheSize),
+	}
+
+	target := checks.GlobalTarget{
+		Url:      "test",
+		LastSeen: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	calls := 0
+	httpmock.RegisterResponder("GET", "http://test/api/v4/projects/1/repository/files/test/raw?ref=main",
+		func(req *http.Request) (*http.Response, error) {
+			calls++
+			if req.Header.Get("If-None-Match") == `"etag-1"` {
+				return httpmock.NewStringResponse(http.StatusNotModified, ""), nil
+			}
+			resp, err := httpmock.NewJsonResponse(http.StatusOK, target)
+			if err != nil {
+				return nil, err
+			}
+			resp.Header.Set("ETag", `"etag-1"`)
+			return resp, nil
+		},
+	)
+
+	got, err := g.fetchFile(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("fetchFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, target) {
+		t.Fatalf("fetchFile() got = %v, want %v", got, target)
+	}
+
+	got, err = g.fetchFile(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("fetchFile() second call error = %v", err)
+	}
+	if !reflect.DeepEqual(got, target) {
+		t.Fatalf("fetchFile() second call got = %v, want %v", got, target)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 requests to be made, got %d", calls)
+	}
+}
+
 func TestClient_PostFile(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
@@ -0,0 +1,82 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Config is the configuration for the metrics and tracing subsystem
+type Config struct {
+	// Exporter configures where traces are sent to
+	Exporter Exporter `yaml:"exporter" mapstructure:"exporter"`
+	// Sampling configures how traces are sampled before being exported
+	Sampling Sampling `yaml:"sampling" mapstructure:"sampling"`
+}
+
+// ExporterType is the protocol used to export traces
+type ExporterType string
+
+const (
+	ExporterGRPC   ExporterType = "grpc"
+	ExporterHTTP   ExporterType = "http"
+	ExporterStdout ExporterType = "stdout"
+)
+
+// Exporter configures the trace exporter
+type Exporter struct {
+	// Type selects the exporter implementation
+	Type ExporterType `yaml:"type" mapstructure:"type"`
+	// Endpoint is the OTLP collector endpoint, e.g. "localhost:4317"
+	Endpoint string `yaml:"endpoint" mapstructure:"endpoint"`
+}
+
+// Create builds the span exporter configured by e
+func (e Exporter) Create(ctx context.Context, _ *Config) (sdktrace.SpanExporter, error) {
+	switch e.Type {
+	case ExporterGRPC:
+		return otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(e.Endpoint), otlptracegrpc.WithInsecure())
+	case ExporterHTTP:
+		return otlptracehttp.New(ctx, otlptracehttp.WithEndpoint(e.Endpoint), otlptracehttp.WithInsecure())
+	default:
+		return nil, fmt.Errorf("unsupported exporter type: %s", e.Type)
+	}
+}
+
+// CreateMetricExporter builds the OTLP metric exporter configured by e,
+// using the same Type/Endpoint as the trace exporter so both signals are
+// shipped to the same collector.
+func (e Exporter) CreateMetricExporter(ctx context.Context) (sdkmetric.Exporter, error) {
+	switch e.Type {
+	case ExporterGRPC:
+		return otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithEndpoint(e.Endpoint), otlpmetricgrpc.WithInsecure())
+	case ExporterHTTP:
+		return otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpoint(e.Endpoint), otlpmetrichttp.WithInsecure())
+	default:
+		return nil, fmt.Errorf("unsupported exporter type: %s", e.Type)
+	}
+}
@@ -20,13 +20,17 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/caas-team/sparrow/internal/logger"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
+	otelprom "go.opentelemetry.io/contrib/bridges/prometheus"
 	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
@@ -41,6 +45,10 @@ type Provider interface {
 	GetRegistry() *prometheus.Registry
 	// InitTracing initializes the OpenTelemetry tracing
 	InitTracing(ctx context.Context) error
+	// InitMetrics initializes the OpenTelemetry metrics pipeline, pushing
+	// both the OTel-native instruments and the Prometheus registry to the
+	// configured OTLP endpoint
+	InitMetrics(ctx context.Context) error
 	// Shutdown closes the metrics and tracing
 	Shutdown(ctx context.Context) error
 }
@@ -49,6 +57,16 @@ type manager struct {
 	config   Config
 	registry *prometheus.Registry
 	tp       *sdktrace.TracerProvider
+	mp       *sdkmetric.MeterProvider
+
+	// samplerOnce guards sampler/samplerErr, so a sampler that registers its
+	// own prometheus collectors (e.g. the ratelimited sampler) is only ever
+	// built, and its collectors only ever registered, once per manager -
+	// InitTracing may otherwise be called more than once across a config
+	// reload or a retry after a failed start.
+	samplerOnce sync.Once
+	sampler     sdktrace.Sampler
+	samplerErr  error
 }
 
 // New initializes the metrics and returns the PrometheusMetrics
@@ -73,10 +91,10 @@ func (m *manager) GetRegistry() *prometheus.Registry {
 	return m.registry
 }
 
-// InitTracing initializes the OpenTelemetry tracing
-func (m *manager) InitTracing(ctx context.Context) error {
-	log := logger.FromContext(ctx)
-	res, err := resource.New(ctx,
+// newResource builds the OpenTelemetry resource shared by the tracer and
+// meter providers
+func newResource(ctx context.Context) (*resource.Resource, error) {
+	return resource.New(ctx,
 		resource.WithHost(),
 		resource.WithContainer(),
 		resource.WithAttributes(
@@ -85,6 +103,12 @@ func (m *manager) InitTracing(ctx context.Context) error {
 			semconv.ServiceVersionKey.String("0.1.0"),
 		),
 	)
+}
+
+// InitTracing initializes the OpenTelemetry tracing
+func (m *manager) InitTracing(ctx context.Context) error {
+	log := logger.FromContext(ctx)
+	res, err := newResource(ctx)
 	if err != nil {
 		log.ErrorContext(ctx, "Failed to create resource", "error", err)
 		return fmt.Errorf("failed to create resource: %v", err)
@@ -106,9 +130,17 @@ func (m *manager) InitTracing(ctx context.Context) error {
 		sdktrace.WithMaxQueueSize(maxQueueSize),
 		sdktrace.WithMaxExportBatchSize(maxBatchSize),
 	)
+
+	m.samplerOnce.Do(func() {
+		m.sampler, m.samplerErr = m.config.Sampling.buildSampler(m.registry)
+	})
+	if m.samplerErr != nil {
+		log.ErrorContext(ctx, "Failed to build sampler", "error", m.samplerErr)
+		return fmt.Errorf("failed to build sampler: %w", m.samplerErr)
+	}
+
 	tp := sdktrace.NewTracerProvider(
-		// TODO: Keep track of the sampler if we run into traffic issues due to the high volume of data.
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSampler(m.sampler),
 		sdktrace.WithSpanProcessor(bsp),
 		sdktrace.WithResource(res),
 	)
@@ -118,17 +150,73 @@ func (m *manager) InitTracing(ctx context.Context) error {
 	return nil
 }
 
-// Shutdown closes the metrics and tracing
+// InitMetrics initializes the OpenTelemetry metrics pipeline. It builds a
+// MeterProvider sharing the tracer's resource, with a periodic reader
+// pushing to the configured OTLP endpoint. The Prometheus registry is
+// bridged in as a metric.Producer so collectors registered there - including
+// ones registered by other packages via GetRegistry - are exported over
+// OTLP as well, without requiring a separate Prometheus scrape target.
+func (m *manager) InitMetrics(ctx context.Context) error {
+	log := logger.FromContext(ctx)
+	res, err := newResource(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "Failed to create resource", "error", err)
+		return fmt.Errorf("failed to create resource: %v", err)
+	}
+
+	exporter, err := m.config.Exporter.CreateMetricExporter(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "Failed to create metric exporter", "error", err)
+		return fmt.Errorf("failed to create metric exporter: %w", err)
+	}
+
+	producer := otelprom.NewProducer(otelprom.WithGatherer(m.registry))
+
+	const exportInterval = 15 * time.Second
+	reader := sdkmetric.NewPeriodicReader(exporter,
+		sdkmetric.WithInterval(exportInterval),
+		sdkmetric.WithProducer(producer),
+	)
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(reader),
+	)
+	otel.SetMeterProvider(mp)
+	m.mp = mp
+	log.DebugContext(ctx, "Metrics initialized with new provider", "provider", m.config.Exporter)
+	return nil
+}
+
+// Shutdown closes the metrics and tracing. Both providers are shut down
+// unconditionally - one failing must not leak or silently drop the other's
+// buffered data - and any resulting errors are joined together.
 func (m *manager) Shutdown(ctx context.Context) error {
 	log := logger.FromContext(ctx)
+	var errs []error
+
 	if m.tp != nil {
-		err := m.tp.Shutdown(ctx)
-		if err != nil {
+		if err := m.tp.Shutdown(ctx); err != nil {
 			log.ErrorContext(ctx, "Failed to shutdown tracer provider", "error", err)
-			return fmt.Errorf("failed to shutdown tracer provider: %w", err)
+			errs = append(errs, fmt.Errorf("failed to shutdown tracer provider: %w", err))
+		}
+	}
+
+	if m.mp != nil {
+		if err := m.mp.ForceFlush(ctx); err != nil {
+			log.ErrorContext(ctx, "Failed to flush meter provider", "error", err)
+			errs = append(errs, fmt.Errorf("failed to flush meter provider: %w", err))
+		}
+		if err := m.mp.Shutdown(ctx); err != nil {
+			log.ErrorContext(ctx, "Failed to shutdown meter provider", "error", err)
+			errs = append(errs, fmt.Errorf("failed to shutdown meter provider: %w", err))
 		}
 	}
 
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
 	log.DebugContext(ctx, "Tracing shutdown")
 	return nil
 }
@@ -0,0 +1,182 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SamplingType selects the sampling strategy used for new traces
+type SamplingType string
+
+const (
+	// SamplingAlwaysOn samples every trace
+	SamplingAlwaysOn SamplingType = "always_on"
+	// SamplingAlwaysOff samples no trace
+	SamplingAlwaysOff SamplingType = "always_off"
+	// SamplingTraceIDRatio samples a fraction of traces based on the trace ID
+	SamplingTraceIDRatio SamplingType = "traceidratio"
+	// SamplingParentBasedTraceIDRatio behaves like SamplingTraceIDRatio for root
+	// spans, but otherwise respects the parent's sampling decision
+	SamplingParentBasedTraceIDRatio SamplingType = "parentbased_traceidratio"
+	// SamplingRateLimited caps the number of sampled root spans per second
+	SamplingRateLimited SamplingType = "ratelimited"
+)
+
+// Sampling configures how traces are sampled before being exported.
+// Regardless of Type, the resulting sampler is always wrapped in
+// sdktrace.ParentBased so that a sampling decision made upstream is honored.
+type Sampling struct {
+	// Type selects the sampling strategy
+	Type SamplingType `yaml:"type" mapstructure:"type"`
+	// Ratio is the fraction of traces sampled, used by traceidratio and
+	// parentbased_traceidratio. Must be between 0 and 1.
+	Ratio float64 `yaml:"ratio" mapstructure:"ratio"`
+	// RatePerSecond is the maximum number of root spans sampled per second,
+	// used by the ratelimited sampler
+	RatePerSecond float64 `yaml:"ratePerSecond" mapstructure:"ratePerSecond"`
+}
+
+// buildSampler constructs the sdktrace.Sampler configured by s, wrapped in
+// sdktrace.ParentBased so upstream sampling decisions propagate correctly.
+func (s Sampling) buildSampler(registry *prometheus.Registry) (sdktrace.Sampler, error) {
+	var root sdktrace.Sampler
+
+	switch s.Type {
+	case SamplingAlwaysOn, "":
+		root = sdktrace.AlwaysSample()
+	case SamplingAlwaysOff:
+		root = sdktrace.NeverSample()
+	case SamplingTraceIDRatio:
+		root = sdktrace.TraceIDRatioBased(s.Ratio)
+	case SamplingParentBasedTraceIDRatio:
+		root = sdktrace.ParentBased(sdktrace.TraceIDRatioBased(s.Ratio))
+	case SamplingRateLimited:
+		root = newRateLimitedSampler(s.RatePerSecond, registry)
+	default:
+		return nil, fmt.Errorf("unsupported sampling type: %s", s.Type)
+	}
+
+	return sdktrace.ParentBased(root), nil
+}
+
+// rateLimitedSampler is an sdktrace.Sampler that allows at most ratePerSecond
+// root spans to be recorded and exported per second. A simple token bucket,
+// refilled lazily on every ShouldSample call, tracks the available budget.
+// When the budget is exhausted the span is still recorded (so counters and
+// other instrumentation derived from it keep firing) but not exported.
+type rateLimitedSampler struct {
+	ratePerSecond float64
+
+	// tokens is the number of whole tokens currently available, stored as an
+	// int64 so it can be updated atomically without a mutex
+	tokens atomic.Int64
+	// lastRefill is the unix nanosecond timestamp of the last refill
+	lastRefill atomic.Int64
+
+	sampleRate   prometheus.Gauge
+	droppedSpans prometheus.Counter
+}
+
+func newRateLimitedSampler(ratePerSecond float64, registry *prometheus.Registry) *rateLimitedSampler {
+	s := &rateLimitedSampler{
+		ratePerSecond: ratePerSecond,
+		sampleRate: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "sparrow_tracing_effective_sample_rate",
+			Help: "The configured maximum rate, in samples per second, of the rate-limited trace sampler; set once when the sampler is built, it does not follow the observed sampling rate",
+		}),
+		droppedSpans: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "sparrow_tracing_dropped_spans_total",
+			Help: "The total number of spans dropped by the rate-limited sampler because the token budget was exhausted",
+		}),
+	}
+	s.tokens.Store(int64(ratePerSecond))
+	s.lastRefill.Store(time.Now().UnixNano())
+	s.sampleRate.Set(ratePerSecond)
+
+	if registry != nil {
+		registry.MustRegister(s.sampleRate, s.droppedSpans)
+	}
+
+	return s
+}
+
+// refill tops up the token bucket based on the elapsed time since the last
+// refill, never exceeding one second worth of tokens.
+func (s *rateLimitedSampler) refill() {
+	now := time.Now().UnixNano()
+	last := s.lastRefill.Load()
+	elapsed := time.Duration(now - last)
+	if elapsed <= 0 {
+		return
+	}
+
+	replenished := int64(elapsed.Seconds() * s.ratePerSecond)
+	if replenished <= 0 {
+		return
+	}
+	if !s.lastRefill.CompareAndSwap(last, now) {
+		// another goroutine already refilled concurrently
+		return
+	}
+
+	max := int64(s.ratePerSecond)
+	for {
+		cur := s.tokens.Load()
+		next := cur + replenished
+		if next > max {
+			next = max
+		}
+		if s.tokens.CompareAndSwap(cur, next) {
+			return
+		}
+	}
+}
+
+// ShouldSample implements sdktrace.Sampler
+func (s *rateLimitedSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	s.refill()
+
+	for {
+		cur := s.tokens.Load()
+		if cur <= 0 {
+			s.droppedSpans.Inc()
+			return sdktrace.SamplingResult{
+				Decision:   sdktrace.RecordOnly,
+				Attributes: nil,
+			}
+		}
+		if s.tokens.CompareAndSwap(cur, cur-1) {
+			return sdktrace.SamplingResult{
+				Decision:   sdktrace.RecordAndSample,
+				Attributes: nil,
+			}
+		}
+	}
+}
+
+// Description implements sdktrace.Sampler
+func (s *rateLimitedSampler) Description() string {
+	return fmt.Sprintf("RateLimitedSampler{%.2f/s}", s.ratePerSecond)
+}
@@ -0,0 +1,63 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package metrics
+
+import (
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func Test_rateLimitedSampler_ShouldSample(t *testing.T) {
+	s := newRateLimitedSampler(2, nil)
+
+	for i := 0; i < 2; i++ {
+		got := s.ShouldSample(sdktrace.SamplingParameters{})
+		if got.Decision != sdktrace.RecordAndSample {
+			t.Fatalf("ShouldSample() call %d = %v, want RecordAndSample", i+1, got.Decision)
+		}
+	}
+
+	// the token budget is now exhausted; the span should still be recorded,
+	// just not sampled
+	got := s.ShouldSample(sdktrace.SamplingParameters{})
+	if got.Decision != sdktrace.RecordOnly {
+		t.Fatalf("ShouldSample() after budget exhausted = %v, want RecordOnly", got.Decision)
+	}
+}
+
+func Test_rateLimitedSampler_refillCapsAtRate(t *testing.T) {
+	s := newRateLimitedSampler(1, nil)
+	s.tokens.Store(0)
+
+	// simulate a long time having passed since the last refill
+	s.lastRefill.Store(s.lastRefill.Load() - int64(10*1e9))
+	s.refill()
+
+	if got := s.tokens.Load(); got != int64(s.ratePerSecond) {
+		t.Fatalf("refill() left tokens = %d, want capped at %d", got, int64(s.ratePerSecond))
+	}
+}
+
+func Test_buildSampler_unsupportedType(t *testing.T) {
+	_, err := Sampling{Type: "bogus"}.buildSampler(nil)
+	if err == nil {
+		t.Fatal("buildSampler() with unsupported type: want error, got nil")
+	}
+}
@@ -0,0 +1,93 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package notifier
+
+import (
+	"fmt"
+	"time"
+)
+
+// BackendType selects a notifier implementation
+type BackendType string
+
+const (
+	BackendSMTP    BackendType = "smtp"
+	BackendWebhook BackendType = "webhook"
+	BackendSlack   BackendType = "slack"
+)
+
+// Config configures every notifier sparrow should deliver target/check
+// state transitions to
+type Config struct {
+	Notifiers []NotifierConfig `yaml:"notifiers" mapstructure:"notifiers"`
+}
+
+// NotifierConfig configures a single notifier backend, the events it
+// should receive, and its retry policy
+type NotifierConfig struct {
+	// Type selects the backend implementation
+	Type BackendType `yaml:"type" mapstructure:"type"`
+	// Events restricts which event types this notifier receives. At least
+	// one event type must be configured; New rejects an empty list rather
+	// than silently delivering every event.
+	Events []EventType `yaml:"events" mapstructure:"events"`
+	// Retry configures the exponential-backoff retry policy applied to
+	// delivery attempts for this notifier
+	Retry RetryConfig `yaml:"retry" mapstructure:"retry"`
+
+	SMTP    *SMTPConfig    `yaml:"smtp,omitempty" mapstructure:"smtp"`
+	Webhook *WebhookConfig `yaml:"webhook,omitempty" mapstructure:"webhook"`
+	Slack   *SlackConfig   `yaml:"slack,omitempty" mapstructure:"slack"`
+}
+
+// RetryConfig configures an exponential-backoff retry policy. A zero value
+// disables retries: a notifier is attempted once.
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of delivery attempts. 0 or 1 means
+	// no retries are performed.
+	MaxAttempts int `yaml:"maxAttempts" mapstructure:"maxAttempts"`
+	// InitialDelay is the delay before the first retry. It doubles after
+	// every subsequent failed attempt, up to MaxDelay.
+	InitialDelay time.Duration `yaml:"initialDelay" mapstructure:"initialDelay"`
+	// MaxDelay caps the delay between retries
+	MaxDelay time.Duration `yaml:"maxDelay" mapstructure:"maxDelay"`
+}
+
+// build constructs the backend Notifier configured by nc
+func (nc NotifierConfig) build() (Notifier, error) {
+	switch nc.Type {
+	case BackendSMTP:
+		if nc.SMTP == nil {
+			return nil, fmt.Errorf("smtp notifier requires smtp configuration")
+		}
+		return newSMTPNotifier(*nc.SMTP), nil
+	case BackendWebhook:
+		if nc.Webhook == nil {
+			return nil, fmt.Errorf("webhook notifier requires webhook configuration")
+		}
+		return newWebhookNotifier(*nc.Webhook), nil
+	case BackendSlack:
+		if nc.Slack == nil {
+			return nil, fmt.Errorf("slack notifier requires slack configuration")
+		}
+		return newSlackNotifier(*nc.Slack), nil
+	default:
+		return nil, fmt.Errorf("unsupported notifier type: %s", nc.Type)
+	}
+}
@@ -0,0 +1,172 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package notifier delivers target/check state transitions to external
+// systems such as email, a generic webhook, or Slack.
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//go:generate moq -out notifier_moq.go . Notifier
+
+// EventType classifies a target/check state transition
+type EventType string
+
+const (
+	// EventTargetAdded is emitted when a target newly appears in the
+	// healthy global target list
+	EventTargetAdded EventType = "target_added"
+	// EventTargetRemoved is emitted when a target disappears from the
+	// healthy global target list, e.g. because it crossed
+	// unhealthyThreshold or was removed from the backing source
+	EventTargetRemoved EventType = "target_removed"
+	// EventRegistrationFailed is emitted when this instance fails to
+	// register or renew its own entry in the target source
+	EventRegistrationFailed EventType = "registration_failed"
+)
+
+// Event describes a single target/check state transition
+type Event struct {
+	// Type classifies the transition
+	Type EventType
+	// TargetURL is the URL of the target the event relates to
+	TargetURL string
+	// LastSeen is the last time the target was seen healthy
+	LastSeen time.Time
+	// Reason is a human-readable explanation of why the event occurred
+	Reason string
+}
+
+// Notifier delivers Events to an external system
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+var tracer = otel.Tracer("github.com/caas-team/sparrow/pkg/sparrow/notifier")
+
+// New builds a Notifier that fans an Event out to every backend configured
+// in cfg. Each backend is wrapped, in order, with its configured event
+// filter, its configured exponential-backoff retry policy, and a span
+// emitted around every notification attempt.
+func New(cfg Config) (Notifier, error) {
+	notifiers := make([]Notifier, 0, len(cfg.Notifiers))
+	for _, nc := range cfg.Notifiers {
+		if len(nc.Events) == 0 {
+			return nil, fmt.Errorf("%s notifier must configure at least one event type", nc.Type)
+		}
+
+		backend, err := nc.build()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build %s notifier: %w", nc.Type, err)
+		}
+
+		var n Notifier = &tracingNotifier{next: backend, name: string(nc.Type)}
+		if nc.Retry.MaxAttempts > 0 {
+			n = &retryNotifier{next: n, config: nc.Retry}
+		}
+		n = &filteredNotifier{next: n, events: toEventSet(nc.Events)}
+		notifiers = append(notifiers, n)
+	}
+
+	return &multiNotifier{notifiers: notifiers}, nil
+}
+
+// multiNotifier fans a single Event out to every wrapped Notifier
+// concurrently, so one slow or failing backend does not delay the others.
+type multiNotifier struct {
+	notifiers []Notifier
+}
+
+// Notify implements Notifier
+func (m *multiNotifier) Notify(ctx context.Context, event Event) error {
+	if len(m.notifiers) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(m.notifiers))
+	var wg sync.WaitGroup
+	for i, n := range m.notifiers {
+		wg.Add(1)
+		go func(i int, n Notifier) {
+			defer wg.Done()
+			errs[i] = n.Notify(ctx, event)
+		}(i, n)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// filteredNotifier only forwards Events whose Type is in events
+type filteredNotifier struct {
+	next   Notifier
+	events map[EventType]struct{}
+}
+
+// Notify implements Notifier
+func (f *filteredNotifier) Notify(ctx context.Context, event Event) error {
+	if _, ok := f.events[event.Type]; !ok {
+		return nil
+	}
+	return f.next.Notify(ctx, event)
+}
+
+func toEventSet(events []EventType) map[EventType]struct{} {
+	set := make(map[EventType]struct{}, len(events))
+	for _, e := range events {
+		set[e] = struct{}{}
+	}
+	return set
+}
+
+// tracingNotifier emits a span around every notification attempt of next,
+// so failures surface alongside the traces emitted by the rest of sparrow.
+type tracingNotifier struct {
+	next Notifier
+	name string
+}
+
+// Notify implements Notifier
+func (t *tracingNotifier) Notify(ctx context.Context, event Event) error {
+	ctx, span := tracer.Start(ctx, "notifier.Notify",
+		trace.WithAttributes(
+			attribute.String("notifier.type", t.name),
+			attribute.String("event.type", string(event.Type)),
+			attribute.String("event.target_url", event.TargetURL),
+		),
+	)
+	defer span.End()
+
+	err := t.next.Notify(ctx, event)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
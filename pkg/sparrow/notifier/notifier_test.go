@@ -0,0 +1,129 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubNotifier is a Notifier that fails its first failUntil calls with err,
+// then succeeds
+type stubNotifier struct {
+	mu        sync.Mutex
+	calls     int
+	failUntil int
+	err       error
+}
+
+func (s *stubNotifier) Notify(_ context.Context, _ Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls <= s.failUntil {
+		return s.err
+	}
+	return nil
+}
+
+func Test_retryNotifier_retriesUntilSuccess(t *testing.T) {
+	stub := &stubNotifier{failUntil: 2, err: errors.New("boom")}
+	r := &retryNotifier{next: stub, config: RetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}}
+
+	if err := r.Notify(context.Background(), Event{}); err != nil {
+		t.Fatalf("Notify() error = %v, want nil after succeeding on the final attempt", err)
+	}
+	if stub.calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", stub.calls)
+	}
+}
+
+func Test_retryNotifier_exhaustsAttempts(t *testing.T) {
+	stub := &stubNotifier{failUntil: 10, err: errors.New("boom")}
+	r := &retryNotifier{next: stub, config: RetryConfig{MaxAttempts: 2, InitialDelay: time.Millisecond}}
+
+	err := r.Notify(context.Background(), Event{})
+	if err == nil {
+		t.Fatal("Notify() error = nil, want error after exhausting retries")
+	}
+	if stub.calls != 2 {
+		t.Fatalf("expected 2 attempts, got %d", stub.calls)
+	}
+}
+
+func Test_retryNotifier_respectsContextCancellation(t *testing.T) {
+	stub := &stubNotifier{failUntil: 10, err: errors.New("boom")}
+	r := &retryNotifier{next: stub, config: RetryConfig{MaxAttempts: 5, InitialDelay: time.Hour}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := r.Notify(ctx, Event{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Notify() error = %v, want context.Canceled", err)
+	}
+	if stub.calls != 1 {
+		t.Fatalf("expected exactly 1 attempt before the canceled context was observed, got %d", stub.calls)
+	}
+}
+
+func Test_filteredNotifier_onlyForwardsConfiguredEvents(t *testing.T) {
+	stub := &stubNotifier{}
+	f := &filteredNotifier{next: stub, events: toEventSet([]EventType{EventTargetAdded})}
+
+	if err := f.Notify(context.Background(), Event{Type: EventTargetRemoved}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if stub.calls != 0 {
+		t.Fatalf("expected non-matching event to be filtered out, next was called %d times", stub.calls)
+	}
+
+	if err := f.Notify(context.Background(), Event{Type: EventTargetAdded}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if stub.calls != 1 {
+		t.Fatalf("expected matching event to be forwarded, next was called %d times", stub.calls)
+	}
+}
+
+func Test_multiNotifier_fansOutAndJoinsErrors(t *testing.T) {
+	ok := &stubNotifier{}
+	failing := &stubNotifier{failUntil: 1, err: errors.New("boom")}
+	m := &multiNotifier{notifiers: []Notifier{ok, failing}}
+
+	err := m.Notify(context.Background(), Event{})
+	if err == nil {
+		t.Fatal("Notify() error = nil, want the failing backend's error to be joined in")
+	}
+	if ok.calls != 1 || failing.calls != 1 {
+		t.Fatalf("expected both backends to be called exactly once, got ok=%d failing=%d", ok.calls, failing.calls)
+	}
+}
+
+func Test_New_rejectsNotifierWithNoEvents(t *testing.T) {
+	_, err := New(Config{Notifiers: []NotifierConfig{
+		{Type: BackendSlack, Slack: &SlackConfig{WebhookURL: "http://example.invalid"}},
+	}})
+	if err == nil {
+		t.Fatal("New() error = nil, want error for a notifier with no configured event types")
+	}
+}
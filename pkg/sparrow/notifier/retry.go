@@ -0,0 +1,61 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// retryNotifier retries a failed delivery with exponential backoff, making
+// at most config.MaxAttempts delivery attempts in total
+type retryNotifier struct {
+	next   Notifier
+	config RetryConfig
+}
+
+// Notify implements Notifier
+func (r *retryNotifier) Notify(ctx context.Context, event Event) error {
+	delay := r.config.InitialDelay
+
+	var err error
+	for attempt := 1; attempt <= r.config.MaxAttempts; attempt++ {
+		if err = r.next.Notify(ctx, event); err == nil {
+			return nil
+		}
+
+		if attempt == r.config.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if r.config.MaxDelay > 0 && delay > r.config.MaxDelay {
+			delay = r.config.MaxDelay
+		}
+	}
+
+	return fmt.Errorf("notifier failed after %d attempts: %w", r.config.MaxAttempts, err)
+}
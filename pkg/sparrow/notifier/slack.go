@@ -0,0 +1,82 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SlackConfig configures the Slack notifier backend, which delivers events
+// to a Slack-compatible incoming webhook
+type SlackConfig struct {
+	// WebhookURL is the incoming webhook URL to POST to
+	WebhookURL string `yaml:"webhookUrl" mapstructure:"webhookUrl"`
+}
+
+var _ Notifier = (*slackNotifier)(nil)
+
+type slackNotifier struct {
+	cfg    SlackConfig
+	client *http.Client
+}
+
+func newSlackNotifier(cfg SlackConfig) *slackNotifier {
+	return &slackNotifier{cfg: cfg, client: http.DefaultClient}
+}
+
+// slackMessage is the minimal payload understood by Slack-compatible
+// incoming webhooks
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// Notify implements Notifier
+func (n *slackNotifier) Notify(ctx context.Context, event Event) error {
+	text := fmt.Sprintf("*%s*\ntarget: `%s`\nlast seen: %s",
+		event.Type, event.TargetURL, event.LastSeen.Format("2006-01-02T15:04:05Z07:00"))
+	if event.Reason != "" {
+		text += fmt.Sprintf("\nreason: %s", event.Reason)
+	}
+
+	body, err := json.Marshal(slackMessage{Text: text})
+	if err != nil {
+		return fmt.Errorf("failed to marshal slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver slack message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("slack webhook returned unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+)
+
+// SMTPConfig configures the SMTP notifier backend
+type SMTPConfig struct {
+	Host     string   `yaml:"host" mapstructure:"host"`
+	Port     int      `yaml:"port" mapstructure:"port"`
+	Username string   `yaml:"username" mapstructure:"username"`
+	Password string   `yaml:"password" mapstructure:"password"`
+	From     string   `yaml:"from" mapstructure:"from"`
+	To       []string `yaml:"to" mapstructure:"to"`
+}
+
+var _ Notifier = (*smtpNotifier)(nil)
+
+type smtpNotifier struct {
+	cfg SMTPConfig
+}
+
+func newSMTPNotifier(cfg SMTPConfig) *smtpNotifier {
+	return &smtpNotifier{cfg: cfg}
+}
+
+// Notify implements Notifier
+func (n *smtpNotifier) Notify(_ context.Context, event Event) error {
+	addr := fmt.Sprintf("%s:%d", n.cfg.Host, n.cfg.Port)
+	auth := smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+
+	subject := fmt.Sprintf("Subject: sparrow: %s\r\n", event.Type)
+	body := fmt.Sprintf("Target: %s\r\nLast seen: %s\r\nReason: %s\r\n",
+		event.TargetURL, event.LastSeen.Format("2006-01-02T15:04:05Z07:00"), event.Reason)
+	msg := []byte(subject + "\r\n" + body)
+
+	if err := smtp.SendMail(addr, auth, n.cfg.From, n.cfg.To, msg); err != nil {
+		return fmt.Errorf("failed to send notification email: %w", err)
+	}
+	return nil
+}
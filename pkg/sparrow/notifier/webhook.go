@@ -0,0 +1,81 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// WebhookConfig configures the webhook notifier backend
+type WebhookConfig struct {
+	// URL is the endpoint the event is POSTed to
+	URL string `yaml:"url" mapstructure:"url"`
+	// Secret, if set, is used to HMAC-SHA256 sign the request body. The
+	// signature is sent in the X-Sparrow-Signature header as a hex string.
+	Secret string `yaml:"secret" mapstructure:"secret"`
+}
+
+var _ Notifier = (*webhookNotifier)(nil)
+
+type webhookNotifier struct {
+	cfg    WebhookConfig
+	client *http.Client
+}
+
+func newWebhookNotifier(cfg WebhookConfig) *webhookNotifier {
+	return &webhookNotifier{cfg: cfg, client: http.DefaultClient}
+}
+
+// Notify implements Notifier
+func (n *webhookNotifier) Notify(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if n.cfg.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.cfg.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Sparrow-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook returned unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package targets
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/caas-team/sparrow/pkg/sparrow/consul"
+	"github.com/caas-team/sparrow/pkg/sparrow/github"
+	"github.com/caas-team/sparrow/pkg/sparrow/gitlab"
+	"github.com/caas-team/sparrow/pkg/sparrow/notifier"
+)
+
+// Backend selects which TargetManager implementation a sparrow instance
+// uses for global target discovery and self-registration
+type Backend string
+
+const (
+	BackendGitlab Backend = "gitlab"
+	BackendGithub Backend = "github"
+	BackendConsul Backend = "consul"
+)
+
+// Config discriminates which TargetManager Backend to construct, plus the
+// knobs shared by every backend's reconciliation loop
+type Config struct {
+	// Type selects the backend implementation
+	Type Backend `yaml:"type" mapstructure:"type"`
+	// Name is the DNS name this instance registers itself under
+	Name string `yaml:"name" mapstructure:"name"`
+	// CheckInterval is how often the gitlab and github backends poll for
+	// target changes. Unused by the consul backend, which instead reacts
+	// to a blocking KV query.
+	CheckInterval time.Duration `yaml:"checkInterval" mapstructure:"checkInterval"`
+	// UnhealthyThreshold is the amount of time a target can be unhealthy
+	// before it is removed from the global target list
+	UnhealthyThreshold time.Duration `yaml:"unhealthyThreshold" mapstructure:"unhealthyThreshold"`
+	// RegistrationInterval is how often the instance renews its own
+	// registration as a global target
+	RegistrationInterval time.Duration `yaml:"registrationInterval" mapstructure:"registrationInterval"`
+
+	Gitlab GitlabConfig  `yaml:"gitlab,omitempty" mapstructure:"gitlab"`
+	Github GithubConfig  `yaml:"github,omitempty" mapstructure:"github"`
+	Consul consul.Config `yaml:"consul,omitempty" mapstructure:"consul"`
+}
+
+// GitlabConfig configures the gitlab.Client used by the gitlab backend
+type GitlabConfig struct {
+	BaseURL   string `yaml:"baseUrl" mapstructure:"baseUrl"`
+	ProjectID int    `yaml:"projectId" mapstructure:"projectId"`
+	Token     string `yaml:"token" mapstructure:"token"`
+}
+
+// GithubConfig configures the github.Client used by the github backend
+type GithubConfig struct {
+	Owner string `yaml:"owner" mapstructure:"owner"`
+	Repo  string `yaml:"repo" mapstructure:"repo"`
+	Dir   string `yaml:"dir" mapstructure:"dir"`
+	// Token is used as a personal access token when App is not set
+	Token string `yaml:"token" mapstructure:"token"`
+	// App, if set, authenticates as a GitHub App installation instead of
+	// using Token directly
+	App *GithubAppConfig `yaml:"app,omitempty" mapstructure:"app"`
+}
+
+// GithubAppConfig configures GitHub App installation authentication
+type GithubAppConfig struct {
+	AppID          int64  `yaml:"appId" mapstructure:"appId"`
+	InstallationID int64  `yaml:"installationId" mapstructure:"installationId"`
+	PrivateKeyPath string `yaml:"privateKeyPath" mapstructure:"privateKeyPath"`
+}
+
+// tokenSource builds the github.TokenSource configured by c
+func (c GithubConfig) tokenSource() (github.TokenSource, error) {
+	if c.App == nil {
+		return github.PATTokenSource(c.Token), nil
+	}
+
+	key, err := os.ReadFile(c.App.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read github app private key: %w", err)
+	}
+
+	return &github.AppTokenSource{
+		AppID:          c.App.AppID,
+		InstallationID: c.App.InstallationID,
+		PrivateKeyPEM:  key,
+	}, nil
+}
+
+// NewFromConfig builds the TargetManager selected by cfg.Type. n may be nil
+// to disable notifications; registry may be nil to disable the metrics
+// collected by the chosen backend.
+func NewFromConfig(cfg Config, n notifier.Notifier, registry *prometheus.Registry) (TargetManager, error) {
+	// registry is a *prometheus.Registry; passed as-is to a
+	// prometheus.Registerer parameter a nil *Registry would wrap into a
+	// non-nil interface, so it is normalized to a true nil interface here
+	var reg prometheus.Registerer
+	if registry != nil {
+		reg = registry
+	}
+
+	switch cfg.Type {
+	case BackendGitlab:
+		client := gitlab.NewClient(cfg.Gitlab.BaseURL, cfg.Gitlab.ProjectID, cfg.Gitlab.Token, reg)
+		return NewGitlabManager(client, n, cfg.Name, cfg.CheckInterval, cfg.UnhealthyThreshold, cfg.RegistrationInterval), nil
+
+	case BackendGithub:
+		tokens, err := cfg.Github.tokenSource()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build github token source: %w", err)
+		}
+		client := github.NewClient(cfg.Github.Owner, cfg.Github.Repo, cfg.Github.Dir, tokens)
+		return NewGithubManager(client, n, cfg.Name, cfg.CheckInterval, cfg.UnhealthyThreshold, cfg.RegistrationInterval), nil
+
+	case BackendConsul:
+		client, err := consul.New(cfg.Consul)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build consul client: %w", err)
+		}
+		return NewConsulManager(client, cfg.Name, cfg.RegistrationInterval), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported target manager backend: %s", cfg.Type)
+	}
+}
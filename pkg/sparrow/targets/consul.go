@@ -0,0 +1,181 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package targets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/caas-team/sparrow/pkg/checks"
+	"github.com/caas-team/sparrow/pkg/sparrow/consul"
+
+	"github.com/caas-team/sparrow/internal/logger"
+)
+
+var _ TargetManager = &consulTargetManager{}
+
+// consulTargetManager implements TargetManager using Consul's KV store as
+// the source of truth for global sparrow peers, instead of a git repository.
+//
+// Unlike gitlabTargetManager it does not poll on a fixed checkInterval:
+// target changes are observed near real-time through Consul's blocking
+// query API. unhealthyThreshold has no direct equivalent here either - it
+// is enforced by Consul itself via the session TTL backing each instance's
+// registration, so a peer that stops renewing its session is dropped from
+// the KV tree automatically.
+type consulTargetManager struct {
+	targets []checks.GlobalTarget
+	mu      sync.RWMutex
+	done    chan struct{}
+	consul  consul.Consul
+
+	// the DNS name used for self-registration
+	name string
+	// how often the instance should renew its global target registration
+	registrationInterval time.Duration
+	// whether the instance has already registered itself as a global target
+	registered bool
+
+	// waitIndex is the Consul KV index to block from on the next
+	// ListTargets call
+	waitIndex uint64
+}
+
+// NewConsulManager creates a new consulTargetManager
+func NewConsulManager(c consul.Consul, name string, registrationInterval time.Duration) *consulTargetManager {
+	return &consulTargetManager{
+		consul:               c,
+		name:                 name,
+		registrationInterval: registrationInterval,
+		mu:                   sync.RWMutex{},
+		done:                 make(chan struct{}, 1),
+	}
+}
+
+// Reconcile reconciles the targets of the consulTargetManager. Instead of a
+// fixed checkInterval, it keeps a blocking Consul KV query in flight and
+// reacts as soon as it returns, either because the tree changed or because
+// the long-poll timed out.
+func (t *consulTargetManager) Reconcile(ctx context.Context) {
+	log := logger.FromContext(ctx).With("name", "ReconcileGlobalTargets")
+	log.Debug("Starting consul global target reconciler")
+
+	registrationTimer := time.NewTimer(t.registrationInterval)
+	defer registrationTimer.Stop()
+
+	refresh := make(chan struct{}, 1)
+	refresh <- struct{}{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := ctx.Err(); err != nil {
+				log.Error("Context canceled", "error", err)
+				if err := t.Shutdown(ctx); err != nil {
+					log.Error("Failed to shutdown gracefully", "error", err)
+				}
+				return
+			}
+		case <-t.done:
+			log.Info("Ending Reconcile routine.")
+			return
+		case <-registrationTimer.C:
+			if err := t.updateRegistration(ctx); err != nil {
+				log.Error("Failed to register consul target", "error", err)
+			}
+			registrationTimer.Reset(t.registrationInterval)
+		case <-refresh:
+			go t.blockForChanges(ctx, refresh)
+		}
+	}
+}
+
+// blockForChanges performs a single blocking ListTargets call and, once it
+// returns, updates the known targets and signals Reconcile to start the
+// next blocking query.
+func (t *consulTargetManager) blockForChanges(ctx context.Context, refresh chan<- struct{}) {
+	log := logger.FromContext(ctx).With("name", "blockForChanges")
+
+	targets, index, err := t.consul.ListTargets(ctx, t.waitIndex)
+	if err != nil {
+		log.Error("Failed to list consul targets", "error", err)
+		// avoid busy-looping if consul is unreachable
+		time.Sleep(time.Second)
+		refresh <- struct{}{}
+		return
+	}
+
+	t.mu.Lock()
+	t.targets = targets
+	t.waitIndex = index
+	t.mu.Unlock()
+
+	log.Debug("Updated global targets", "targets", len(targets))
+	refresh <- struct{}{}
+}
+
+// GetTargets returns the current targets of the consulTargetManager
+func (t *consulTargetManager) GetTargets() []checks.GlobalTarget {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.targets
+}
+
+// Shutdown shuts down the consulTargetManager and deregisters the session
+// backing this instance's registration
+func (t *consulTargetManager) Shutdown(ctx context.Context) error {
+	log := logger.FromContext(ctx).With("name", "Shutdown")
+	log.Debug("Shutting down consul target manager")
+	t.mu.Lock()
+	t.registered = false
+	t.mu.Unlock()
+
+	t.done <- struct{}{}
+	return t.consul.Deregister(ctx)
+}
+
+// updateRegistration registers the current instance as a global target
+func (t *consulTargetManager) updateRegistration(ctx context.Context) error {
+	log := logger.FromContext(ctx).With("name", t.name, "registered", t.Registered())
+	log.Debug("Updating registration")
+
+	target := checks.GlobalTarget{
+		Url:      fmt.Sprintf("https://%s", t.name),
+		LastSeen: time.Now().UTC(),
+	}
+
+	if err := t.consul.Register(ctx, t.name, target); err != nil {
+		log.Error("Failed to register with consul", "error", err)
+		return err
+	}
+
+	t.mu.Lock()
+	t.registered = true
+	t.mu.Unlock()
+	log.Debug("Successfully registered")
+	return nil
+}
+
+func (t *consulTargetManager) Registered() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.registered
+}
@@ -0,0 +1,215 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package targets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/caas-team/sparrow/pkg/checks"
+	"github.com/caas-team/sparrow/pkg/sparrow/github"
+	"github.com/caas-team/sparrow/pkg/sparrow/notifier"
+
+	"github.com/caas-team/sparrow/internal/logger"
+)
+
+var _ TargetManager = &githubTargetManager{}
+
+// githubTargetManager implements TargetManager, reading and writing global
+// targets through the GitHub Contents API. It follows the same fixed
+// checkInterval/registrationInterval polling shape as gitlabTargetManager,
+// since the Contents API has no blocking-query equivalent to Consul's.
+type githubTargetManager struct {
+	targets  []checks.GlobalTarget
+	mu       sync.RWMutex
+	done     chan struct{}
+	github   github.Github
+	notifier notifier.Notifier
+	// the DNS name used for self-registration
+	name string
+	// the interval for the target reconciliation process
+	checkInterval time.Duration
+	// the amount of time a target can be
+	// unhealthy before it is removed from the global target list
+	unhealthyThreshold time.Duration
+	// how often the instance should register itself as a global target
+	registrationInterval time.Duration
+	// whether the instance has already registered itself as a global target
+	registered bool
+}
+
+// NewGithubManager creates a new githubTargetManager. n may be nil, in
+// which case target/check state transitions are not notified anywhere.
+func NewGithubManager(g github.Github, n notifier.Notifier, name string, checkInterval, unhealthyThreshold, regInterval time.Duration) *githubTargetManager {
+	return &githubTargetManager{
+		github:               g,
+		notifier:             n,
+		name:                 name,
+		checkInterval:        checkInterval,
+		registrationInterval: regInterval,
+		unhealthyThreshold:   unhealthyThreshold,
+		mu:                   sync.RWMutex{},
+		done:                 make(chan struct{}, 1),
+	}
+}
+
+// Reconcile reconciles the targets of the githubTargetManager. The global
+// targets are parsed from a GitHub repository directory and evaluated for
+// healthiness; unhealthy targets are removed.
+func (t *githubTargetManager) Reconcile(ctx context.Context) {
+	log := logger.FromContext(ctx).With("name", "ReconcileGlobalTargets")
+	log.Debug("Starting global githubTargetManager reconciler")
+
+	checkTimer := time.NewTimer(t.checkInterval)
+	registrationTimer := time.NewTimer(t.registrationInterval)
+
+	defer checkTimer.Stop()
+	defer registrationTimer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := ctx.Err(); err != nil {
+				log.Error("Context canceled", "error", err)
+				if err := t.Shutdown(ctx); err != nil {
+					log.Error("Failed to shutdown gracefully", "error", err)
+				}
+				return
+			}
+		case <-t.done:
+			log.Info("Ending Reconcile routine.")
+			return
+		case <-checkTimer.C:
+			if err := t.refreshTargets(ctx); err != nil {
+				log.Error("Failed to get global githubTargetManager", "error", err)
+				continue
+			}
+			checkTimer.Reset(t.checkInterval)
+		case <-registrationTimer.C:
+			if err := t.updateRegistration(ctx); err != nil {
+				log.Error("Failed to register global githubTargetManager", "error", err)
+				continue
+			}
+			registrationTimer.Reset(t.registrationInterval)
+		}
+	}
+}
+
+// GetTargets returns the current targets of the githubTargetManager
+func (t *githubTargetManager) GetTargets() []checks.GlobalTarget {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.targets
+}
+
+// Shutdown shuts down the githubTargetManager
+func (t *githubTargetManager) Shutdown(ctx context.Context) error {
+	log := logger.FromContext(ctx).With("name", "Shutdown")
+	log.Debug("Shutting down global githubTargetManager")
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.registered = false
+	t.done <- struct{}{}
+	return nil
+}
+
+// updateRegistration registers the current instance as a global target
+func (t *githubTargetManager) updateRegistration(ctx context.Context) error {
+	log := logger.FromContext(ctx).With("name", t.name, "registered", t.Registered())
+	log.Debug("Updating registration")
+
+	f := github.File{
+		CommitMessage: "Updated registration",
+		Content:       checks.GlobalTarget{Url: fmt.Sprintf("https://%s", t.name), LastSeen: time.Now().UTC()},
+	}
+
+	if t.Registered() {
+		if err := t.github.PutFile(ctx, f); err != nil {
+			log.Error("Failed to update registration", "error", err)
+			notifyAll(ctx, t.notifier, []notifier.Event{{
+				Type:      notifier.EventRegistrationFailed,
+				TargetURL: f.Content.Url,
+				LastSeen:  f.Content.LastSeen,
+				Reason:    err.Error(),
+			}})
+			return err
+		}
+		log.Debug("Successfully updated registration")
+		return nil
+	}
+
+	f.CommitMessage = "Initial registration"
+	if err := t.github.PostFile(ctx, f); err != nil {
+		log.Error("Failed to register global githubTargetManager", "error", err)
+		notifyAll(ctx, t.notifier, []notifier.Event{{
+			Type:      notifier.EventRegistrationFailed,
+			TargetURL: f.Content.Url,
+			LastSeen:  f.Content.LastSeen,
+			Reason:    err.Error(),
+		}})
+		return err
+	}
+
+	log.Debug("Successfully registered")
+	t.mu.Lock()
+	t.registered = true
+	t.mu.Unlock()
+	return nil
+}
+
+// refreshTargets updates the targets of the githubTargetManager with the
+// latest available healthy targets
+func (t *githubTargetManager) refreshTargets(ctx context.Context) error {
+	log := logger.FromContext(ctx).With("name", "updateGlobalTargets")
+
+	targets, err := t.github.FetchFiles(ctx)
+	if err != nil {
+		log.Error("Failed to update global targets", "error", err)
+		return err
+	}
+
+	var healthyTargets []checks.GlobalTarget
+	for _, target := range targets {
+		if time.Now().Add(-t.unhealthyThreshold).After(target.LastSeen) {
+			log.Debug("Skipping unhealthy target", "target", target)
+			continue
+		}
+		healthyTargets = append(healthyTargets, target)
+	}
+
+	t.mu.Lock()
+	previousTargets := t.targets
+	t.targets = healthyTargets
+	t.mu.Unlock()
+
+	// notify after releasing the lock: a slow or down notifier backend must
+	// not stall GetTargets or the next reconcile tick
+	notifyAll(ctx, t.notifier, transitionEvents(previousTargets, healthyTargets))
+
+	log.Debug("Updated global targets", "targets", len(healthyTargets))
+	return nil
+}
+
+func (t *githubTargetManager) Registered() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.registered
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/caas-team/sparrow/pkg/checks"
 	"github.com/caas-team/sparrow/pkg/sparrow/gitlab"
+	"github.com/caas-team/sparrow/pkg/sparrow/notifier"
 
 	"github.com/caas-team/sparrow/internal/logger"
 )
@@ -16,10 +17,11 @@ var _ TargetManager = &gitlabTargetManager{}
 
 // gitlabTargetManager implements TargetManager
 type gitlabTargetManager struct {
-	targets []checks.GlobalTarget
-	mu      sync.RWMutex
-	done    chan struct{}
-	gitlab  gitlab.Gitlab
+	targets  []checks.GlobalTarget
+	mu       sync.RWMutex
+	done     chan struct{}
+	gitlab   gitlab.Gitlab
+	notifier notifier.Notifier
 	// the DNS name used for self-registration
 	name string
 	// the interval for the target reconciliation process
@@ -33,10 +35,12 @@ type gitlabTargetManager struct {
 	registered bool
 }
 
-// NewGitlabManager creates a new gitlabTargetManager
-func NewGitlabManager(g gitlab.Gitlab, name string, checkInterval, unhealthyThreshold, regInterval time.Duration) *gitlabTargetManager {
+// NewGitlabManager creates a new gitlabTargetManager. n may be nil, in
+// which case target/check state transitions are not notified anywhere.
+func NewGitlabManager(g gitlab.Gitlab, n notifier.Notifier, name string, checkInterval, unhealthyThreshold, regInterval time.Duration) *gitlabTargetManager {
 	return &gitlabTargetManager{
 		gitlab:               g,
+		notifier:             n,
 		name:                 name,
 		checkInterval:        checkInterval,
 		registrationInterval: regInterval,
@@ -114,7 +118,7 @@ func (t *gitlabTargetManager) Shutdown(ctx context.Context) error {
 
 // updateRegistration registers the current instance as a global target
 func (t *gitlabTargetManager) updateRegistration(ctx context.Context) error {
-	log := logger.FromContext(ctx).With("name", t.name, "registered", t.registered)
+	log := logger.FromContext(ctx).With("name", t.name, "registered", t.Registered())
 	log.Debug("Updating registration")
 
 	f := gitlab.File{
@@ -125,29 +129,37 @@ func (t *gitlabTargetManager) updateRegistration(ctx context.Context) error {
 	}
 
 	if t.Registered() {
-		t.mu.Lock()
-		defer t.mu.Unlock()
 		f.CommitMessage = "Updated registration"
-		err := t.gitlab.PutFile(ctx, f)
-		if err != nil {
+		if err := t.gitlab.PutFile(ctx, f); err != nil {
 			log.Error("Failed to update registration", "error", err)
+			notifyAll(ctx, t.notifier, []notifier.Event{{
+				Type:      notifier.EventRegistrationFailed,
+				TargetURL: f.Content.Url,
+				LastSeen:  f.Content.LastSeen,
+				Reason:    err.Error(),
+			}})
 			return err
 		}
 		log.Debug("Successfully updated registration")
 		return nil
 	}
 
-	t.mu.Lock()
-	defer t.mu.Unlock()
 	f.CommitMessage = "Initial registration"
-	err := t.gitlab.PostFile(ctx, f)
-	if err != nil {
+	if err := t.gitlab.PostFile(ctx, f); err != nil {
 		log.Error("Failed to register global gitlabTargetManager", "error", err)
+		notifyAll(ctx, t.notifier, []notifier.Event{{
+			Type:      notifier.EventRegistrationFailed,
+			TargetURL: f.Content.Url,
+			LastSeen:  f.Content.LastSeen,
+			Reason:    err.Error(),
+		}})
 		return err
 	}
 
 	log.Debug("Successfully registered")
+	t.mu.Lock()
 	t.registered = true
+	t.mu.Unlock()
 	return nil
 }
 
@@ -155,9 +167,7 @@ func (t *gitlabTargetManager) updateRegistration(ctx context.Context) error {
 // with the latest available healthy targets
 func (t *gitlabTargetManager) refreshTargets(ctx context.Context) error {
 	log := logger.FromContext(ctx).With("name", "updateGlobalTargets")
-	t.mu.Lock()
-	var healthyTargets []checks.GlobalTarget
-	defer t.mu.Unlock()
+
 	targets, err := t.gitlab.FetchFiles(ctx)
 	if err != nil {
 		log.Error("Failed to update global targets", "error", err)
@@ -165,6 +175,7 @@ func (t *gitlabTargetManager) refreshTargets(ctx context.Context) error {
 	}
 
 	// filter unhealthy targets - this may be removed in the future
+	var healthyTargets []checks.GlobalTarget
 	for _, target := range targets {
 		if time.Now().Add(-t.unhealthyThreshold).After(target.LastSeen) {
 			log.Debug("Skipping unhealthy target", "target", target)
@@ -173,8 +184,16 @@ func (t *gitlabTargetManager) refreshTargets(ctx context.Context) error {
 		healthyTargets = append(healthyTargets, target)
 	}
 
+	t.mu.Lock()
+	previousTargets := t.targets
 	t.targets = healthyTargets
-	log.Debug("Updated global targets", "targets", len(t.targets))
+	t.mu.Unlock()
+
+	// notify after releasing the lock: a slow or down notifier backend must
+	// not stall GetTargets or the next reconcile tick
+	notifyAll(ctx, t.notifier, transitionEvents(previousTargets, healthyTargets))
+
+	log.Debug("Updated global targets", "targets", len(healthyTargets))
 	return nil
 }
 
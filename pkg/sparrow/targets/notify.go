@@ -0,0 +1,81 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package targets
+
+import (
+	"context"
+
+	"github.com/caas-team/sparrow/pkg/checks"
+	"github.com/caas-team/sparrow/pkg/sparrow/notifier"
+
+	"github.com/caas-team/sparrow/internal/logger"
+)
+
+// transitionEvents compares previous and current and returns an Event for
+// every target that newly appeared in current, and every target that
+// disappeared - either because it crossed unhealthyThreshold or was removed
+// from the backing source entirely. It is shared by every TargetManager
+// backend that notifies on target transitions.
+func transitionEvents(previous, current []checks.GlobalTarget) []notifier.Event {
+	previousByURL := make(map[string]checks.GlobalTarget, len(previous))
+	for _, target := range previous {
+		previousByURL[target.Url] = target
+	}
+	currentByURL := make(map[string]checks.GlobalTarget, len(current))
+	for _, target := range current {
+		currentByURL[target.Url] = target
+	}
+
+	var events []notifier.Event
+	for url, target := range currentByURL {
+		if _, ok := previousByURL[url]; !ok {
+			events = append(events, notifier.Event{
+				Type:      notifier.EventTargetAdded,
+				TargetURL: target.Url,
+				LastSeen:  target.LastSeen,
+			})
+		}
+	}
+	for url, target := range previousByURL {
+		if _, ok := currentByURL[url]; !ok {
+			events = append(events, notifier.Event{
+				Type:      notifier.EventTargetRemoved,
+				TargetURL: target.Url,
+				LastSeen:  target.LastSeen,
+				Reason:    "target no longer present in the healthy global target list",
+			})
+		}
+	}
+	return events
+}
+
+// notifyAll sends every event in events to n, logging but otherwise ignoring
+// delivery failures. n may be nil, in which case notifyAll is a no-op.
+// Callers must not hold a lock while calling notifyAll: n may retry with
+// backoff, which would otherwise stall anything waiting on that lock.
+func notifyAll(ctx context.Context, n notifier.Notifier, events []notifier.Event) {
+	if n == nil {
+		return
+	}
+	for _, event := range events {
+		if err := n.Notify(ctx, event); err != nil {
+			logger.FromContext(ctx).Error("Failed to send notification", "error", err, "event", event.Type)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// sparrow
+// (C) 2024, Deutsche Telekom IT GmbH
+//
+// Deutsche Telekom IT GmbH and all other contributors /
+// copyright owners license this file to you under the Apache
+// License, Version 2.0 (the "License"); you may not use this
+// file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package targets
+
+import (
+	"context"
+
+	"github.com/caas-team/sparrow/pkg/checks"
+)
+
+//go:generate moq -out targets_moq.go . TargetManager
+
+// TargetManager manages the global targets of a sparrow instance. A sparrow
+// registers itself with the backend it is configured with and periodically
+// reconciles its view of the other, healthy instances.
+type TargetManager interface {
+	// Reconcile starts the reconciliation process that keeps the known
+	// global targets up to date and maintains this instance's own
+	// registration. It blocks until ctx is canceled or Shutdown is called.
+	Reconcile(ctx context.Context)
+	// GetTargets returns the currently known, healthy global targets
+	GetTargets() []checks.GlobalTarget
+	// Shutdown stops the reconciliation process. The consul backend also
+	// removes this instance's registration by destroying its session; the
+	// gitlab and github backends leave their registration file in place.
+	Shutdown(ctx context.Context) error
+}